@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"math"
 	"net/http"
-	"os/exec"
+	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
@@ -14,16 +16,53 @@ import (
 	"time"
 
 	"github.com/labstack/echo"
+
+	"github.com/ZygmuntJakub/gfs-stats-go/pkg/cache"
+	"github.com/ZygmuntJakub/gfs-stats-go/pkg/gfs"
+	"github.com/ZygmuntJakub/gfs-stats-go/pkg/grib2"
+	"github.com/ZygmuntJakub/gfs-stats-go/pkg/nws"
+	"github.com/ZygmuntJakub/gfs-stats-go/pkg/scheduler"
+)
+
+// periodHoursHourly and periodHoursMultiDay select how many consecutive
+// GFS forecast hours /forecast/hourly and /forecast/periods each bucket
+// into a single nws.Period.
+const (
+	periodHoursHourly   = 1
+	periodHoursMultiDay = 12
+)
+
+const (
+	cacheDir        = "./forecast_cache"
+	cacheTTL        = 6 * time.Hour
+	cacheMaxEntries = 1000
 )
 
+const (
+	gfsDataDir         = "./gfs_data"
+	schedulerStateFile = "./gfs_data_scheduler_state.json"
+)
+
+var forecastCache *cache.FileCache
+
+func init() {
+	var err error
+	forecastCache, err = cache.NewFileCache(cacheDir, cacheTTL, cacheMaxEntries)
+	if err != nil {
+		log.Fatalf("failed to initialize forecast cache: %v", err)
+	}
+}
+
 type ForecastData struct {
-	Timestamp time.Time `json:"timestamp"`
-	Temp2m    float64   `json:"temp_2m"`
-	UWind10m  float64   `json:"u_wind_10m"`
-	VWind10m  float64   `json:"v_wind_10m"`
-	WindGust  float64   `json:"wind_gust"`
-	WindSpeed float64   `json:"wind_speed"`
-	WindDir   float64   `json:"wind_direction"`
+	Timestamp     time.Time `json:"timestamp"`
+	Temp2m        float64   `json:"temp_2m"`
+	UWind10m      float64   `json:"u_wind_10m"`
+	VWind10m      float64   `json:"v_wind_10m"`
+	WindGust      float64   `json:"wind_gust"`
+	WindSpeed     float64   `json:"wind_speed"`
+	WindDir       float64   `json:"wind_direction"`
+	CloudCoverPct float64   `json:"cloud_cover_pct"`
+	PrecipRate    float64   `json:"precip_rate"` // kg/m^2/s
 }
 
 type ForecastOutput struct {
@@ -35,6 +74,10 @@ type ForecastOutput struct {
 }
 
 func main() {
+	downloader := gfs.NewDownloader(gfs.NOMADSSource{}, gfsDataDir)
+	sched := scheduler.NewScheduler(downloader, schedulerStateFile)
+	go sched.Start(context.Background())
+
 	e := echo.New()
 
 	e.GET("/forecast", func(c echo.Context) error {
@@ -58,65 +101,75 @@ func main() {
 		return c.JSON(http.StatusOK, forecast)
 	})
 
+	e.GET("/forecast/hourly", func(c echo.Context) error {
+		return serveNWSForecast(c, periodHoursHourly)
+	})
+
+	e.GET("/forecast/periods", func(c echo.Context) error {
+		return serveNWSForecast(c, periodHoursMultiDay)
+	})
+
 	e.Logger.Fatal(e.Start(":8080"))
 }
 
-func Ingest(lon, lat float64) ([]ForecastOutput, error) {
-	// Check if wgrib2 is installed
-	if _, err := exec.LookPath("wgrib2"); err != nil {
-		log.Fatal("wgrib2 must be installed")
+func serveNWSForecast(c echo.Context, periodHours int) error {
+	lonFloat, err := strconv.ParseFloat(c.QueryParam("lon"), 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err)
 	}
-
-	// Get list of GFS files
-	files, err := filepath.Glob("./gfs_data/gfs.t*z.pgrb2.0p25.f*")
+	latFloat, err := strconv.ParseFloat(c.QueryParam("lat"), 64)
 	if err != nil {
-		log.Fatalf("Error finding GFS files: %v", err)
+		return c.JSON(http.StatusBadRequest, err)
 	}
 
-	// Sort files by forecast hour
-	sort.Slice(files, func(i, j int) bool {
-		// Extract forecast hour from filename
-		hi := getForecastHour(files[i])
-		hj := getForecastHour(files[j])
-		return hi < hj
-	})
-
-	// Process files in parallel
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	var forecasts []ForecastData
-
-	// Limit concurrency to avoid overwhelming the system
-	maxWorkers := 4
-	sem := make(chan struct{}, maxWorkers)
+	forecasts, err := collectForecastData(lonFloat, latFloat)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err)
+	}
 
-	for _, file := range files {
-		wg.Add(1)
-		sem <- struct{}{} // Acquire semaphore
+	points := make([]nws.HourlyPoint, 0, len(forecasts))
+	for _, f := range forecasts {
+		windSpeed := math.Sqrt(f.UWind10m*f.UWind10m + f.VWind10m*f.VWind10m)
+		windDirRad := math.Atan2(-f.UWind10m, -f.VWind10m)
+		windDir := windDirRad * 180 / math.Pi
+		if windDir < 0 {
+			windDir += 360
+		}
 
-		go func(f string) {
-			defer wg.Done()
-			defer func() { <-sem }() // Release semaphore when done
+		points = append(points, nws.HourlyPoint{
+			Time:            f.Timestamp,
+			Lat:             latFloat,
+			Lon:             lonFloat,
+			TempC:           f.Temp2m - 273.15,
+			WindKt:          windSpeed * 1.94384,
+			GustKt:          f.WindGust * 1.94384,
+			Direction:       degreesToCardinal(windDir),
+			CloudCoverPct:   f.CloudCoverPct,
+			PrecipRateKgM2S: f.PrecipRate,
+		})
+	}
 
-			data, err := processGFSFile(f, lon, lat)
-			if err != nil {
-				log.Printf("Error processing %s: %v", f, err)
-				return
-			}
+	return c.JSON(http.StatusOK, nws.BuildForecast(points, periodHours))
+}
 
-			mu.Lock()
-			forecasts = append(forecasts, data)
-			mu.Unlock()
-		}(file)
+func Ingest(lon, lat float64) ([]ForecastOutput, error) {
+	date, cycle, err := currentDiskCycle()
+	if err != nil {
+		return nil, err
 	}
+	cacheKey := cache.Key(lat, lon, date+cycle)
 
-	// Wait for all workers to finish
-	wg.Wait()
+	if cached, ok := forecastCache.Get(cacheKey); ok {
+		var output []ForecastOutput
+		if err := json.Unmarshal(cached, &output); err == nil {
+			return output, nil
+		}
+	}
 
-	// Sort forecasts by timestamp
-	sort.Slice(forecasts, func(i, j int) bool {
-		return forecasts[i].Timestamp.Before(forecasts[j].Timestamp)
-	})
+	forecasts, err := collectForecastData(lon, lat)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create a map to store the latest forecast for each timestamp
 	timestampMap := make(map[string]ForecastOutput)
@@ -179,9 +232,96 @@ func Ingest(lon, lat float64) ([]ForecastOutput, error) {
 		)
 	}
 
+	if data, err := json.Marshal(output); err == nil {
+		if err := forecastCache.Set(cacheKey, data); err != nil {
+			log.Printf("failed to cache forecast: %v", err)
+		}
+	}
+
 	return output, nil
 }
 
+// currentDiskCycle returns the GFS cycle hour actually backing the files
+// in gfsDataDir, read from one of their names, rather than guessing at
+// the "current" cycle from wall-clock time. A guess can run ahead of
+// what's actually been downloaded, which would otherwise cache a response
+// computed from the old cycle's files under the new cycle's key and keep
+// serving it stale until the new cycle's data lands. The file names carry
+// no date, so the date returned is the current UTC date, not the date of
+// the cycle on disk.
+func currentDiskCycle() (date, cycle string, err error) {
+	files, err := filepath.Glob(gfsDataDir + "/gfs.t*z.pgrb2.0p25.f*")
+	if err != nil {
+		return "", "", fmt.Errorf("finding GFS files: %w", err)
+	}
+	if len(files) == 0 {
+		return "", "", fmt.Errorf("no GFS files found in %s", gfsDataDir)
+	}
+
+	base := filepath.Base(files[0])
+	parts := strings.Split(base, ".")
+	if len(parts) < 2 {
+		return "", "", fmt.Errorf("invalid filename format: %s", files[0])
+	}
+
+	cycle = strings.TrimSuffix(strings.TrimPrefix(parts[1], "t"), "z")
+	return time.Now().UTC().Format("20060102"), cycle, nil
+}
+
+// collectForecastData decodes every GFS forecast-hour file on disk for
+// the given coordinate, in parallel, and returns the results sorted by
+// timestamp.
+func collectForecastData(lon, lat float64) ([]ForecastData, error) {
+	files, err := filepath.Glob(gfsDataDir + "/gfs.t*z.pgrb2.0p25.f*")
+	if err != nil {
+		return nil, fmt.Errorf("finding GFS files: %w", err)
+	}
+
+	// Sort files by forecast hour
+	sort.Slice(files, func(i, j int) bool {
+		hi := getForecastHour(files[i])
+		hj := getForecastHour(files[j])
+		return hi < hj
+	})
+
+	// Process files in parallel
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var forecasts []ForecastData
+
+	// Limit concurrency to avoid overwhelming the system
+	maxWorkers := 4
+	sem := make(chan struct{}, maxWorkers)
+
+	for _, file := range files {
+		wg.Add(1)
+		sem <- struct{}{} // Acquire semaphore
+
+		go func(f string) {
+			defer wg.Done()
+			defer func() { <-sem }() // Release semaphore when done
+
+			data, err := processGFSFile(f, lon, lat)
+			if err != nil {
+				log.Printf("Error processing %s: %v", f, err)
+				return
+			}
+
+			mu.Lock()
+			forecasts = append(forecasts, data)
+			mu.Unlock()
+		}(file)
+	}
+
+	wg.Wait()
+
+	sort.Slice(forecasts, func(i, j int) bool {
+		return forecasts[i].Timestamp.Before(forecasts[j].Timestamp)
+	})
+
+	return forecasts, nil
+}
+
 // degreesToCardinal converts wind direction in degrees to cardinal direction
 func degreesToCardinal(degrees float64) string {
 	// Normalize degrees to be within 0-360
@@ -210,9 +350,20 @@ func getForecastHour(filename string) int {
 	return hour
 }
 
+// levelAboveGround identifies a "N m above ground" fixed surface in GRIB2
+// Code Table 4.5.
+const levelAboveGround uint8 = 103
+
+// levelSurface identifies the "surface" fixed surface in GRIB2 Code Table 4.5.
+const levelSurface uint8 = 1
+
+// levelEntireAtmosphere identifies the "entire atmosphere (considered as a
+// single layer)" fixed surface in GRIB2 Code Table 4.5, the level GFS
+// reports total cloud cover on.
+const levelEntireAtmosphere uint8 = 200
+
 func processGFSFile(file string, lon, lat float64) (ForecastData, error) {
 	var data ForecastData
-	var err error
 
 	// Extract timestamp from filename
 	base := filepath.Base(file)
@@ -221,8 +372,6 @@ func processGFSFile(file string, lon, lat float64) (ForecastData, error) {
 		return data, fmt.Errorf("invalid filename format: %s", file)
 	}
 
-	// In the processGFSFile function, replace the timestamp parsing part with:
-
 	// Parse timestamp (format: gfs.t00z.pgrb2.0p25.f000)
 	cycleHour := strings.TrimSuffix(strings.TrimPrefix(parts[1], "t"), "z")
 	cycleTime, err := time.Parse("200601021504", time.Now().Format("20060102")+cycleHour+"00")
@@ -237,60 +386,53 @@ func processGFSFile(file string, lon, lat float64) (ForecastData, error) {
 	}
 	data.Timestamp = cycleTime.Add(time.Duration(forecastHour) * time.Hour)
 
-	// Run wgrib2 and pipe to awk to extract values
-	wgrib2Cmd := exec.Command("wgrib2", file, "-match", ":(TMP:2 m above ground|UGRD:10 m above ground|VGRD:10 m above ground|GUST:surface):", "-lon", fmt.Sprintf("%.3f", lon), fmt.Sprintf("%.3f", lat))
-	awkCmd := exec.Command("awk", "-Fval=", "{print $2}")
-
-	// Create a pipe to connect wgrib2 output to awk input
-	pipe, err := wgrib2Cmd.StdoutPipe()
+	f, err := os.Open(file)
 	if err != nil {
-		return data, fmt.Errorf("error creating pipe: %v", err)
+		return data, fmt.Errorf("opening %s: %w", file, err)
 	}
+	defer f.Close()
 
-	// Set awk's input to come from the pipe
-	awkCmd.Stdin = pipe
-
-	// Start wgrib2 command
-	if err := wgrib2Cmd.Start(); err != nil {
-		return data, fmt.Errorf("error starting wgrib2: %v", err)
-	}
-
-	// Run awk and capture output
-	output, err := awkCmd.Output()
+	messages, err := grib2.Decode(f)
 	if err != nil {
-		return data, fmt.Errorf("error running awk: %v", err)
+		return data, fmt.Errorf("decoding %s: %w", file, err)
 	}
 
-	// Wait for wgrib2 to finish
-	if err := wgrib2Cmd.Wait(); err != nil {
-		return data, fmt.Errorf("wgrib2 error: %v", err)
+	found := 0
+	for _, msg := range messages {
+		if assignField(&data, msg, lat, lon) {
+			found++
+		}
 	}
 
-	// Parse output lines and extract values
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(lines) < 4 { // We expect at least 4 values (TMP, UGRD, VGRD, GUST)
+	if found < 4 { // We expect TMP, UGRD, VGRD and GUST
 		return data, fmt.Errorf("insufficient data in file: %s", file)
 	}
 
-	data.WindGust, err = strconv.ParseFloat(lines[0], 64)
-	if err != nil {
-		return data, fmt.Errorf("error parsing wind gust: %v", err)
-	}
-
-	data.Temp2m, err = strconv.ParseFloat(lines[1], 64)
-	if err != nil {
-		return data, fmt.Errorf("error parsing temperature: %v", err)
-	}
-
-	data.UWind10m, err = strconv.ParseFloat(lines[2], 64)
-	if err != nil {
-		return data, fmt.Errorf("error parsing U wind: %v", err)
-	}
+	return data, nil
+}
 
-	data.VWind10m, err = strconv.ParseFloat(lines[3], 64)
-	if err != nil {
-		return data, fmt.Errorf("error parsing V wind: %v", err)
+// assignField copies msg's value at (lat, lon) into the field of data it
+// corresponds to, if any. It reports whether msg was one of the four
+// fields processGFSFile requires (TMP, UGRD, VGRD, GUST); TCDC and PRATE
+// are optional and always report false even when matched.
+func assignField(data *ForecastData, msg *grib2.Message, lat, lon float64) bool {
+	switch {
+	case msg.ParameterName() == "TMP" && msg.Level.Type == levelAboveGround && msg.Level.Value == 2:
+		data.Temp2m = msg.ValueAt(lat, lon)
+		return true
+	case msg.ParameterName() == "UGRD" && msg.Level.Type == levelAboveGround && msg.Level.Value == 10:
+		data.UWind10m = msg.ValueAt(lat, lon)
+		return true
+	case msg.ParameterName() == "VGRD" && msg.Level.Type == levelAboveGround && msg.Level.Value == 10:
+		data.VWind10m = msg.ValueAt(lat, lon)
+		return true
+	case msg.ParameterName() == "GUST" && msg.Level.Type == levelSurface:
+		data.WindGust = msg.ValueAt(lat, lon)
+		return true
+	case msg.ParameterName() == "TCDC" && msg.Level.Type == levelEntireAtmosphere:
+		data.CloudCoverPct = msg.ValueAt(lat, lon)
+	case msg.ParameterName() == "PRATE" && msg.Level.Type == levelSurface:
+		data.PrecipRate = msg.ValueAt(lat, lon)
 	}
-
-	return data, nil
+	return false
 }
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/ZygmuntJakub/gfs-stats-go/pkg/grib2"
+	"github.com/ZygmuntJakub/gfs-stats-go/pkg/grib2/grib2test"
+)
+
+func decodeTestMessage(t *testing.T, discipline, category, number, surfaceType uint8, surfaceValue uint32, value float32) *grib2.Message {
+	t.Helper()
+	raw := grib2test.Build(grib2test.Message{
+		Discipline:    discipline,
+		Ni:            1,
+		Nj:            1,
+		Di:            250000, // 0.25 deg
+		Dj:            250000,
+		Category:      category,
+		Number:        number,
+		TimeRangeUnit: 1,
+		SurfaceType:   surfaceType,
+		SurfaceValue:  surfaceValue,
+		Value:         value,
+	})
+	messages, err := grib2.Decode(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("grib2.Decode: %v", err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("got %d messages, want 1", len(messages))
+	}
+	return messages[0]
+}
+
+func TestAssignField(t *testing.T) {
+	const lat, lon = 0.0, 0.0
+
+	cases := []struct {
+		name                         string
+		discipline, category, number uint8
+		surfaceType                  uint8
+		surfaceValue                 uint32
+		wantRequired                 bool
+		field                        func(*ForecastData) float64
+	}{
+		{"TMP 2m above ground", 0, 0, 0, levelAboveGround, 2, true, func(d *ForecastData) float64 { return d.Temp2m }},
+		{"UGRD 10m above ground", 0, 2, 2, levelAboveGround, 10, true, func(d *ForecastData) float64 { return d.UWind10m }},
+		{"VGRD 10m above ground", 0, 2, 3, levelAboveGround, 10, true, func(d *ForecastData) float64 { return d.VWind10m }},
+		{"GUST surface", 0, 2, 22, levelSurface, 0, true, func(d *ForecastData) float64 { return d.WindGust }},
+		{"TCDC entire atmosphere", 0, 6, 1, levelEntireAtmosphere, 0, false, func(d *ForecastData) float64 { return d.CloudCoverPct }},
+		{"PRATE surface", 0, 1, 7, levelSurface, 0, false, func(d *ForecastData) float64 { return d.PrecipRate }},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := decodeTestMessage(t, tc.discipline, tc.category, tc.number, tc.surfaceType, tc.surfaceValue, 42)
+
+			var data ForecastData
+			required := assignField(&data, msg, lat, lon)
+
+			if required != tc.wantRequired {
+				t.Errorf("assignField() required = %v, want %v", required, tc.wantRequired)
+			}
+			if got := tc.field(&data); math.Abs(got-42) > 1e-3 {
+				t.Errorf("field = %v, want ~42", got)
+			}
+		})
+	}
+}
+
+func TestAssignFieldWrongLevelDoesNotMatch(t *testing.T) {
+	// TMP at 10m above ground isn't the 2m temperature processGFSFile wants.
+	msg := decodeTestMessage(t, 0, 0, 0, levelAboveGround, 10, 42)
+
+	var data ForecastData
+	if assignField(&data, msg, 0, 0) {
+		t.Errorf("assignField() matched TMP at 10m above ground, want no match")
+	}
+	if data.Temp2m != 0 {
+		t.Errorf("Temp2m = %v, want untouched (0)", data.Temp2m)
+	}
+}
@@ -0,0 +1,28 @@
+// Package cache provides a forecast cache keyed by coordinate and GFS
+// cycle, so repeated requests for the same point don't re-run the full
+// download-and-decode pipeline until a new cycle makes the cached data
+// stale.
+package cache
+
+import "fmt"
+
+// Cache stores opaque, caller-serialized forecast payloads by key. The
+// default implementation is FileCache; swapping in a Redis- or
+// memcached-backed implementation only requires satisfying this interface.
+type Cache interface {
+	// Get returns the cached value for key and whether it was found and
+	// still fresh. A miss (ok == false) covers both "never cached" and
+	// "cached but stale".
+	Get(key string) (value []byte, ok bool)
+
+	// Set stores value under key, evicting the least recently used entry
+	// first if the cache is already at its configured capacity.
+	Set(key string, value []byte) error
+}
+
+// Key builds the cache key this package expects: one entry per
+// coordinate/cycle pair, rounded to ~11 m of precision so nearby requests
+// for "the same point" share a cache entry.
+func Key(lat, lon float64, cycle string) string {
+	return fmt.Sprintf("%.4f_%.4f_%s", lat, lon, cycle)
+}
@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"container/list"
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileCache persists cache entries as individual files under Dir,
+// mirroring the loadFromDisk/errTooOld pattern the epaper weather client
+// uses: a cached value is only served if its file's mtime is younger than
+// TTL, otherwise it's treated as a miss and the caller is expected to
+// recompute and Set a fresh value.
+type FileCache struct {
+	dir        string
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	lru     *list.List               // front = most recently used
+	entries map[string]*list.Element // key -> lru element holding the key
+}
+
+// NewFileCache returns a FileCache that stores entries under dir (created
+// if it doesn't exist), expires them after ttl, and keeps at most
+// maxEntries on disk, evicting the least recently used entry first.
+func NewFileCache(dir string, ttl time.Duration, maxEntries int) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	c := &FileCache{
+		dir:        dir,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		lru:        list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+	c.loadExisting()
+	return c, nil
+}
+
+// loadExisting seeds the LRU list from whatever is already on disk,
+// ordered newest-to-oldest by mtime, so a restarted process doesn't
+// immediately evict entries that were actually still warm.
+func (c *FileCache) loadExisting() {
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	type seen struct {
+		key     string
+		modTime time.Time
+	}
+	var all []seen
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		all = append(all, seen{key: idFromFilename(f.Name()), modTime: info.ModTime()})
+	}
+
+	// Newest first, so PushBack builds the list front-to-back in the same
+	// newest-to-oldest order touch() maintains during normal operation:
+	// front is most recently used, back is evicted first.
+	sort.Slice(all, func(i, j int) bool { return all[i].modTime.After(all[j].modTime) })
+	for _, s := range all {
+		c.entries[s.key] = c.lru.PushBack(s.key)
+	}
+}
+
+// Get returns the cached value for key if it exists and is younger than
+// TTL, marking it most recently used.
+func (c *FileCache) Get(key string) ([]byte, bool) {
+	id := fileID(key)
+	path := c.path(id)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+
+	if time.Since(info.ModTime()) > c.ttl {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	c.touch(id)
+	return data, true
+}
+
+// Set writes value under key and evicts the least recently used entry if
+// the cache is now over capacity.
+func (c *FileCache) Set(key string, value []byte) error {
+	id := fileID(key)
+	if err := os.WriteFile(c.path(id), value, 0644); err != nil {
+		return err
+	}
+
+	c.touch(id)
+	c.evictIfNeeded()
+	return nil
+}
+
+func (c *FileCache) touch(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[id]; ok {
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[id] = c.lru.PushFront(id)
+}
+
+func (c *FileCache) evictIfNeeded() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		if oldest == nil {
+			return
+		}
+		id := oldest.Value.(string)
+		os.Remove(c.path(id))
+		c.lru.Remove(oldest)
+		delete(c.entries, id)
+	}
+}
+
+func (c *FileCache) path(id string) string {
+	return filepath.Join(c.dir, id+".cache")
+}
+
+// fileID turns a cache key into the on-disk filename (minus extension)
+// that stores it.
+func fileID(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// idFromFilename recovers a file's id from its name on disk, used when
+// rebuilding the LRU list from whatever NewFileCache finds already
+// present in dir.
+func idFromFilename(name string) string {
+	return name[:len(name)-len(filepath.Ext(name))]
+}
@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// setWithModTime calls Set and then backdates the resulting file's mtime,
+// so tests can control the age ordering loadExisting sees deterministically
+// instead of relying on real wall-clock gaps between calls.
+func setWithModTime(t *testing.T, c *FileCache, key string, modTime time.Time) {
+	t.Helper()
+	if err := c.Set(key, []byte(key)); err != nil {
+		t.Fatalf("Set(%q): %v", key, err)
+	}
+	if err := os.Chtimes(c.path(fileID(key)), modTime, modTime); err != nil {
+		t.Fatalf("Chtimes(%q): %v", key, err)
+	}
+}
+
+func TestLoadExistingEvictsOldestNotNewest(t *testing.T) {
+	dir := t.TempDir()
+
+	seed, err := NewFileCache(dir, time.Hour, 0) // unlimited, just to seed files on disk
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+
+	now := time.Now()
+	setWithModTime(t, seed, "oldest", now.Add(-2*time.Hour))
+	setWithModTime(t, seed, "middle", now.Add(-1*time.Hour))
+	setWithModTime(t, seed, "newest", now)
+
+	// Restart with a cap that forces eviction on the very next write.
+	restarted, err := NewFileCache(dir, time.Hour, 2)
+	if err != nil {
+		t.Fatalf("NewFileCache (restarted): %v", err)
+	}
+	if err := restarted.Set("fresh", []byte("fresh")); err != nil {
+		t.Fatalf("Set(fresh): %v", err)
+	}
+
+	if _, ok := restarted.Get("oldest"); ok {
+		t.Error("Get(oldest) = hit, want evicted (it was on disk longest)")
+	}
+	if _, ok := restarted.Get("middle"); ok {
+		t.Error("Get(middle) = hit, want evicted (maxEntries=2 only leaves room for fresh + newest)")
+	}
+	if _, ok := restarted.Get("newest"); !ok {
+		t.Error("Get(newest) = miss, want hit (it was on disk most recently before restart)")
+	}
+	if _, ok := restarted.Get("fresh"); !ok {
+		t.Error("Get(fresh) = miss, want hit (just written)")
+	}
+}
+
+func TestEvictionRemovesLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewFileCache(dir, time.Hour, 2)
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+
+	if err := c.Set("a", []byte("a")); err != nil {
+		t.Fatalf("Set(a): %v", err)
+	}
+	if err := c.Set("b", []byte("b")); err != nil {
+		t.Fatalf("Set(b): %v", err)
+	}
+
+	// Touch "a" so "b" becomes the least recently used entry.
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("Get(a) = miss, want hit")
+	}
+
+	if err := c.Set("c", []byte("c")); err != nil {
+		t.Fatalf("Set(c): %v", err)
+	}
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("Get(b) = hit, want evicted (least recently used)")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Error("Get(a) = miss, want hit (touched more recently than b)")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Error("Get(c) = miss, want hit (just written)")
+	}
+}
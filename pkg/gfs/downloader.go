@@ -0,0 +1,248 @@
+// Package gfs provides a reusable client for discovering and downloading
+// NOAA Global Forecast System (GFS) GRIB2 files from any of its published
+// mirrors (NOMADS, AWS Open Data, Google Cloud Public Datasets).
+package gfs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultResolution is the GFS grid spacing most consumers want.
+	DefaultResolution = "0p25"
+
+	defaultMaxParallel = 4
+	defaultMaxRetries  = 3
+	defaultRetryDelay  = 5 * time.Second
+)
+
+// DefaultForecastHours covers the first day of a GFS cycle at hourly
+// resolution, matching what the original CLI downloaded.
+var DefaultForecastHours = []string{
+	"000", "001", "002", "003", "004", "005", "006", "007", "008", "009",
+	"010", "011", "012", "013", "014", "015", "016", "017", "018", "019",
+	"020", "021", "022", "023", "024",
+}
+
+// Downloader fetches a set of GFS forecast-hour files for a cycle from a
+// Source and assembles them atomically under OutputDir.
+type Downloader struct {
+	Source        Source
+	Resolution    string
+	OutputDir     string
+	ForecastHours []string
+	MaxParallel   int
+	MaxRetries    int
+	RetryDelay    time.Duration
+	Client        *http.Client
+
+	// Partial, when true, fetches only PartialFields (DefaultFields if
+	// unset) via byte-range requests driven by each file's .idx sidecar
+	// instead of downloading the full GRIB2 file.
+	Partial       bool
+	PartialFields []FieldSpec
+}
+
+// NewDownloader returns a Downloader with the repo's historical defaults
+// (0.25°, forecast hours 000-024) for the given source and output
+// directory. Callers can override any field before calling Run.
+func NewDownloader(source Source, outputDir string) *Downloader {
+	return &Downloader{
+		Source:        source,
+		Resolution:    DefaultResolution,
+		OutputDir:     outputDir,
+		ForecastHours: DefaultForecastHours,
+		MaxParallel:   defaultMaxParallel,
+		MaxRetries:    defaultMaxRetries,
+		RetryDelay:    defaultRetryDelay,
+		Client:        &http.Client{Timeout: 30 * time.Minute},
+	}
+}
+
+// Run downloads every configured forecast hour for the given cycle into a
+// temporary directory next to OutputDir, then atomically swaps it in once
+// all downloads succeed.
+func (d *Downloader) Run(ctx context.Context, date, cycle string) error {
+	if err := os.MkdirAll(filepath.Dir(d.OutputDir), 0755); err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
+	}
+
+	tmpOutputDir, err := os.MkdirTemp(filepath.Dir(d.OutputDir), "gfs_download_")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer func() {
+		if _, err := os.Stat(tmpOutputDir); err == nil {
+			os.RemoveAll(tmpOutputDir)
+		}
+	}()
+
+	log.Printf("Downloading GFS forecast from %s: %s %sZ", d.Source.Name(), date, cycle)
+	log.Printf("Resolution: %s", d.Resolution)
+	log.Printf("Temp output: %s", tmpOutputDir)
+
+	jobs := make(chan string, len(d.ForecastHours))
+	results := make(chan error, len(d.ForecastHours))
+
+	var wg sync.WaitGroup
+	for i := 0; i < d.MaxParallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.worker(ctx, date, cycle, tmpOutputDir, jobs, results)
+		}()
+	}
+
+	for _, hour := range d.ForecastHours {
+		jobs <- hour
+	}
+	close(jobs)
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var failed int
+	for err := range results {
+		if err != nil {
+			log.Printf("Download failed: %v", err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d download(s) failed", failed)
+	}
+
+	if _, err := os.Stat(d.OutputDir); err == nil {
+		if err := os.RemoveAll(d.OutputDir); err != nil {
+			return fmt.Errorf("failed to remove old output directory: %w", err)
+		}
+	}
+
+	if err := os.Rename(tmpOutputDir, d.OutputDir); err != nil {
+		return fmt.Errorf("failed to move temp directory to final location: %w", err)
+	}
+
+	log.Println("All downloads completed and moved to", d.OutputDir)
+	return nil
+}
+
+func (d *Downloader) worker(ctx context.Context, date, cycle, outputDir string, jobs <-chan string, results chan<- error) {
+	for hour := range jobs {
+		select {
+		case <-ctx.Done():
+			results <- ctx.Err()
+			return
+		default:
+			results <- d.downloadWithRetry(ctx, date, cycle, hour, outputDir)
+		}
+	}
+}
+
+func (d *Downloader) downloadWithRetry(ctx context.Context, date, cycle, hour, outputDir string) error {
+	url := d.Source.Location(date, cycle, d.Resolution, hour)
+	filename := filepath.Base(url)
+	outputPath := filepath.Join(outputDir, filename)
+	tempPath := filepath.Join(outputDir, filename+".tmp")
+
+	minSize := int64(1 << 20) // 1MB; a full pgrb2 file is always well over this
+	if d.Partial {
+		minSize = 1 << 10 // a handful of packed fields can be well under 1MB
+	}
+
+	if info, err := os.Stat(outputPath); err == nil {
+		if info.Size() > minSize {
+			log.Printf("✓ %sh exists (%s)", hour, formatSize(info.Size()))
+			return nil
+		}
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= d.MaxRetries; attempt++ {
+		log.Printf("[%sh] Downloading from %s (attempt %d/%d)...", hour, d.Source.Name(), attempt, d.MaxRetries)
+
+		err := d.downloadFile(ctx, url, tempPath)
+		if err == nil {
+			if info, err := os.Stat(tempPath); err == nil && info.Size() > minSize {
+				if err := os.Rename(tempPath, outputPath); err != nil {
+					os.Remove(tempPath)
+					return fmt.Errorf("failed to rename temp file: %w", err)
+				}
+				log.Printf("✓ %sh complete (%s)", hour, formatSize(info.Size()))
+				return nil
+			}
+			lastErr = fmt.Errorf("file too small")
+		} else {
+			lastErr = fmt.Errorf("download failed: %w", err)
+		}
+
+		if attempt < d.MaxRetries {
+			time.Sleep(d.RetryDelay)
+		}
+	}
+
+	os.Remove(tempPath)
+	return fmt.Errorf("%sh failed after %d attempts: %v", hour, d.MaxRetries, lastErr)
+}
+
+func (d *Downloader) downloadFile(ctx context.Context, url, outputPath string) error {
+	out, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("creating output file: %w", err)
+	}
+	defer out.Close()
+
+	if d.Partial {
+		fetcher := NewIndexFetcher(d.Source)
+		fetcher.Client = d.Client
+		if d.PartialFields != nil {
+			fetcher.Fields = d.PartialFields
+		}
+		return fetcher.FetchPartial(ctx, url, out)
+	}
+
+	return d.Source.Fetch(ctx, d.Client, url, out)
+}
+
+// CurrentCycle returns the most recent GFS cycle date and hour that should
+// already be available on the sources, based on NOMADS' typical publish
+// lag after each synoptic hour.
+func CurrentCycle(now time.Time) (date, cycle string) {
+	now = now.UTC()
+	hour := now.Hour()
+	switch {
+	case hour >= 23:
+		return now.Format("20060102"), "18"
+	case hour >= 17:
+		return now.Format("20060102"), "12"
+	case hour >= 11:
+		return now.Format("20060102"), "06"
+	case hour >= 5:
+		return now.Format("20060102"), "00"
+	default:
+		yesterday := now.Add(-24 * time.Hour)
+		return yesterday.Format("20060102"), "18"
+	}
+}
+
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,149 @@
+package gfs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// FieldSpec selects one parameter/level combination out of a GFS file's
+// .idx sidecar, e.g. {"TMP", "2 m above ground"}.
+type FieldSpec struct {
+	Param string
+	Level string
+}
+
+// DefaultFields is the field set cmd/ingest's processGFSFile reads:
+// 2 m temperature, 10 m wind components, surface wind gust, total cloud
+// cover, and precipitation rate. Keep this in sync with assignField in
+// cmd/ingest/main.go — a --partial download that omits one of the first
+// four fields makes processGFSFile reject the file, and one that omits
+// TCDC or PRATE leaves the corresponding ForecastData value silently zeroed.
+var DefaultFields = []FieldSpec{
+	{Param: "TMP", Level: "2 m above ground"},
+	{Param: "UGRD", Level: "10 m above ground"},
+	{Param: "VGRD", Level: "10 m above ground"},
+	{Param: "GUST", Level: "surface"},
+	{Param: "TCDC", Level: "entire atmosphere"},
+	{Param: "PRATE", Level: "surface"},
+}
+
+// IndexRecord is one line of a NOMADS .idx sidecar: the byte offset a
+// record starts at within the full GRIB2 file, and the parameter/level it
+// holds.
+type IndexRecord struct {
+	Number        int
+	Offset        int64
+	ReferenceTime string
+	Param         string
+	Level         string
+	ForecastStep  string
+}
+
+// ParseIndex parses a NOMADS .idx file, whose lines look like:
+//
+//	1:0:d=2024060100:TMP:2 m above ground:anl:
+//	2:123456:d=2024060100:UGRD:10 m above ground:anl:
+func ParseIndex(data []byte) ([]IndexRecord, error) {
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+
+	records := make([]IndexRecord, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		parts := strings.Split(line, ":")
+		if len(parts) < 6 {
+			return nil, fmt.Errorf("malformed index line %q", line)
+		}
+
+		number, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid record number in %q: %w", line, err)
+		}
+
+		offset, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid offset in %q: %w", line, err)
+		}
+
+		records = append(records, IndexRecord{
+			Number:        number,
+			Offset:        offset,
+			ReferenceTime: strings.TrimPrefix(parts[2], "d="),
+			Param:         parts[3],
+			Level:         parts[4],
+			ForecastStep:  parts[5],
+		})
+	}
+
+	return records, nil
+}
+
+// IndexFetcher assembles a partial GRIB2 file containing only the
+// records matching Fields, by reading a file's .idx sidecar and issuing
+// one byte-range request per matching record. Each NOMADS index record
+// corresponds to a complete, standalone GRIB2 message, so the
+// concatenation of the selected ranges is itself a valid multi-message
+// GRIB2 file.
+type IndexFetcher struct {
+	Source Source
+	Client *http.Client
+	Fields []FieldSpec
+}
+
+// NewIndexFetcher returns an IndexFetcher for source using DefaultFields.
+func NewIndexFetcher(source Source) *IndexFetcher {
+	return &IndexFetcher{
+		Source: source,
+		Client: &http.Client{},
+		Fields: DefaultFields,
+	}
+}
+
+// FetchPartial downloads the .idx sidecar for dataURL, then fetches and
+// writes to w only the byte ranges of the records matching f.Fields, in
+// file order.
+func (f *IndexFetcher) FetchPartial(ctx context.Context, dataURL string, w io.Writer) error {
+	var idx bytes.Buffer
+	if err := f.Source.Fetch(ctx, f.Client, dataURL+".idx", &idx); err != nil {
+		return fmt.Errorf("fetching index: %w", err)
+	}
+
+	records, err := ParseIndex(idx.Bytes())
+	if err != nil {
+		return fmt.Errorf("parsing index: %w", err)
+	}
+
+	for i, rec := range records {
+		if !f.matches(rec) {
+			continue
+		}
+
+		end := int64(-1) // open range to EOF for the file's last record
+		if i+1 < len(records) {
+			end = records[i+1].Offset - 1
+		}
+
+		if err := f.Source.FetchRange(ctx, f.Client, dataURL, rec.Offset, end, w); err != nil {
+			return fmt.Errorf("fetching record %d (%s %s): %w", rec.Number, rec.Param, rec.Level, err)
+		}
+	}
+
+	return nil
+}
+
+func (f *IndexFetcher) matches(rec IndexRecord) bool {
+	for _, field := range f.Fields {
+		if rec.Param == field.Param && rec.Level == field.Level {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package gfs
+
+import "testing"
+
+// TestDefaultFieldsCoversIngestRequirements guards against DefaultFields
+// drifting out of sync with cmd/ingest's assignField: a --partial download
+// missing TMP, UGRD, VGRD or GUST makes processGFSFile reject the file, and
+// one missing TCDC or PRATE silently leaves that ForecastData field zeroed.
+func TestDefaultFieldsCoversIngestRequirements(t *testing.T) {
+	want := []FieldSpec{
+		{Param: "TMP", Level: "2 m above ground"},
+		{Param: "UGRD", Level: "10 m above ground"},
+		{Param: "VGRD", Level: "10 m above ground"},
+		{Param: "GUST", Level: "surface"},
+		{Param: "TCDC", Level: "entire atmosphere"},
+		{Param: "PRATE", Level: "surface"},
+	}
+
+	for _, field := range want {
+		found := false
+		for _, got := range DefaultFields {
+			if got == field {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("DefaultFields missing %+v", field)
+		}
+	}
+}
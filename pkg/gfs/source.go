@@ -0,0 +1,159 @@
+package gfs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Source abstracts over the different places a GFS forecast file can be
+// fetched from. Implementations are responsible for turning a cycle
+// (date, cycle hour, forecast hour) into a source-specific location and
+// for performing the actual HTTP transfer.
+type Source interface {
+	// Name identifies the source, used in logs and error messages.
+	Name() string
+
+	// Location returns the fully-qualified URL for a given forecast file.
+	Location(date, cycle, resolution, forecastHour string) string
+
+	// Fetch writes the full contents of the file at url to w.
+	Fetch(ctx context.Context, client *http.Client, url string, w io.Writer) error
+
+	// FetchRange writes the inclusive byte range [start, end] of the file
+	// at url to w. Sources that cannot honor range requests return an error.
+	FetchRange(ctx context.Context, client *http.Client, url string, start, end int64, w io.Writer) error
+}
+
+// fetch performs a plain GET against url and copies the response body to w.
+// Shared by sources whose Fetch implementation has no source-specific quirks.
+func fetch(ctx context.Context, client *http.Client, url string, w io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("downloading file: %w", err)
+	}
+
+	return nil
+}
+
+// fetchRange performs a ranged GET against url and copies the response
+// body to w. An end of -1 requests an open range (start to EOF).
+func fetchRange(ctx context.Context, client *http.Client, url string, start, end int64, w io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	if end < 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", start))
+	} else {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// A 200 means the server ignored the Range header and sent the whole
+	// file, which is only the requested range when that range is all of it.
+	partial := resp.StatusCode == http.StatusPartialContent
+	whole := resp.StatusCode == http.StatusOK && start == 0 && end < 0
+	if !partial && !whole {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("downloading range: %w", err)
+	}
+
+	return nil
+}
+
+// NOMADSSource fetches directly from the NCEP NOMADS HTTPS endpoint, the
+// same location cmd/download originally hard-coded.
+type NOMADSSource struct{}
+
+func (NOMADSSource) Name() string { return "nomads" }
+
+func (NOMADSSource) Location(date, cycle, resolution, forecastHour string) string {
+	filename := fmt.Sprintf("gfs.t%sz.pgrb2.%s.f%s", cycle, resolution, forecastHour)
+	return fmt.Sprintf("https://nomads.ncep.noaa.gov/pub/data/nccf/com/gfs/prod/gfs.%s/%s/atmos/%s", date, cycle, filename)
+}
+
+func (NOMADSSource) Fetch(ctx context.Context, client *http.Client, url string, w io.Writer) error {
+	return fetch(ctx, client, url, w)
+}
+
+func (NOMADSSource) FetchRange(ctx context.Context, client *http.Client, url string, start, end int64, w io.Writer) error {
+	return fetchRange(ctx, client, url, start, end, w)
+}
+
+// AWSSource fetches from the public noaa-gfs-bdp-pds S3 bucket that NOAA
+// publishes as part of the AWS Open Data program.
+type AWSSource struct{}
+
+func (AWSSource) Name() string { return "aws" }
+
+func (AWSSource) Location(date, cycle, resolution, forecastHour string) string {
+	filename := fmt.Sprintf("gfs.t%sz.pgrb2.%s.f%s", cycle, resolution, forecastHour)
+	return fmt.Sprintf("https://noaa-gfs-bdp-pds.s3.amazonaws.com/gfs.%s/%s/atmos/%s", date, cycle, filename)
+}
+
+func (AWSSource) Fetch(ctx context.Context, client *http.Client, url string, w io.Writer) error {
+	return fetch(ctx, client, url, w)
+}
+
+func (AWSSource) FetchRange(ctx context.Context, client *http.Client, url string, start, end int64, w io.Writer) error {
+	return fetchRange(ctx, client, url, start, end, w)
+}
+
+// GCSSource fetches from the Google Cloud Public Datasets mirror of GFS.
+type GCSSource struct{}
+
+func (GCSSource) Name() string { return "gcs" }
+
+func (GCSSource) Location(date, cycle, resolution, forecastHour string) string {
+	filename := fmt.Sprintf("gfs.t%sz.pgrb2.%s.f%s", cycle, resolution, forecastHour)
+	return fmt.Sprintf("https://storage.googleapis.com/global-forecast-system/gfs.%s/%s/atmos/%s", date, cycle, filename)
+}
+
+func (GCSSource) Fetch(ctx context.Context, client *http.Client, url string, w io.Writer) error {
+	return fetch(ctx, client, url, w)
+}
+
+func (GCSSource) FetchRange(ctx context.Context, client *http.Client, url string, start, end int64, w io.Writer) error {
+	return fetchRange(ctx, client, url, start, end, w)
+}
+
+// Sources maps the short names cmd/download's --source flag accepts to
+// the Source they select.
+var Sources = map[string]Source{
+	"nomads": NOMADSSource{},
+	"aws":    AWSSource{},
+	"gcs":    GCSSource{},
+}
+
+// SourceByName returns the Source registered under name, or an error if
+// name isn't one of Sources' keys.
+func SourceByName(name string) (Source, error) {
+	if s, ok := Sources[name]; ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("unknown source %q (valid: nomads, aws, gcs)", name)
+}
@@ -0,0 +1,53 @@
+package gfs
+
+import "testing"
+
+func TestSourceLocation(t *testing.T) {
+	const date, cycle, resolution, forecastHour = "20240601", "00", "0p25", "006"
+
+	cases := []struct {
+		name   string
+		source Source
+		want   string
+	}{
+		{
+			name:   "nomads",
+			source: NOMADSSource{},
+			want:   "https://nomads.ncep.noaa.gov/pub/data/nccf/com/gfs/prod/gfs.20240601/00/atmos/gfs.t00z.pgrb2.0p25.f006",
+		},
+		{
+			name:   "aws",
+			source: AWSSource{},
+			want:   "https://noaa-gfs-bdp-pds.s3.amazonaws.com/gfs.20240601/00/atmos/gfs.t00z.pgrb2.0p25.f006",
+		},
+		{
+			name:   "gcs",
+			source: GCSSource{},
+			want:   "https://storage.googleapis.com/global-forecast-system/gfs.20240601/00/atmos/gfs.t00z.pgrb2.0p25.f006",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.source.Location(date, cycle, resolution, forecastHour); got != tc.want {
+				t.Errorf("Location() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSourceByName(t *testing.T) {
+	for name, want := range Sources {
+		got, err := SourceByName(name)
+		if err != nil {
+			t.Errorf("SourceByName(%q): %v", name, err)
+		}
+		if got != want {
+			t.Errorf("SourceByName(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	if _, err := SourceByName("bogus"); err == nil {
+		t.Error("SourceByName(\"bogus\") = nil error, want error")
+	}
+}
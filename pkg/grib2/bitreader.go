@@ -0,0 +1,33 @@
+package grib2
+
+import "errors"
+
+// bitReader reads fixed-width big-endian bit fields out of a byte slice,
+// the packing GRIB2 Section 7 uses for simple-packed data.
+type bitReader struct {
+	data []byte
+	pos  int // bit offset from the start of data
+}
+
+func newBitReader(data []byte) *bitReader {
+	return &bitReader{data: data}
+}
+
+func (r *bitReader) read(bits int) (uint32, error) {
+	if bits == 0 {
+		return 0, nil
+	}
+	if r.pos+bits > len(r.data)*8 {
+		return 0, errors.New("bitReader: read past end of data")
+	}
+
+	var value uint32
+	for i := 0; i < bits; i++ {
+		byteIdx := r.pos / 8
+		bitIdx := 7 - r.pos%8
+		bit := (r.data[byteIdx] >> uint(bitIdx)) & 1
+		value = value<<1 | uint32(bit)
+		r.pos++
+	}
+	return value, nil
+}
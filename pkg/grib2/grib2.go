@@ -0,0 +1,145 @@
+// Package grib2 decodes WMO GRIB Edition 2 messages, enough of the format
+// to read GFS 0.25° output without shelling out to wgrib2. It supports
+// Sections 0-8 with the regular lat/lon grid (Template 3.0) and simple
+// packing (Template 5.0); JPEG2000-packed fields (Template 5.40) are
+// recognized but not decoded yet.
+package grib2
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ErrUnsupportedPacking is returned by Decode when a message uses a data
+// representation template this package cannot unpack yet (e.g. JPEG2000).
+var ErrUnsupportedPacking = errors.New("grib2: unsupported data representation template")
+
+// Message is a single decoded GRIB2 field: one parameter, one level, one
+// forecast time, on a regular lat/lon grid.
+type Message struct {
+	Discipline    uint8
+	Category      uint8
+	Number        uint8
+	Level         Level
+	ReferenceTime time.Time
+	ForecastTime  time.Duration
+
+	grid   grid
+	values []float64 // row-major, grid.ni columns by grid.nj rows, NaN where missing
+}
+
+// Level identifies the fixed surface a Message's values apply to, e.g.
+// "2 m above ground" or "surface".
+type Level struct {
+	Type  uint8
+	Value float64
+}
+
+// ParameterName returns the short wgrib2-style name for this message's
+// discipline/category/number, e.g. "TMP", "UGRD", falling back to a
+// "<cat>.<num>" placeholder for parameters this package doesn't know.
+func (m *Message) ParameterName() string {
+	return parameterName(m.Discipline, m.Category, m.Number)
+}
+
+// ValueAt returns the field value at (lat, lon) using bilinear
+// interpolation over the message's regular lat/lon grid. It returns NaN
+// if the coordinate falls outside the grid or lands on a bitmapped-out
+// (missing) point.
+func (m *Message) ValueAt(lat, lon float64) float64 {
+	return m.grid.interpolate(m.values, lat, lon)
+}
+
+// Decode reads every GRIB2 message in r until EOF. A GFS pgrb2 file is a
+// concatenation of many single-field messages, so the returned slice
+// typically has one entry per parameter/level combination in the file.
+func Decode(r io.Reader) ([]*Message, error) {
+	br := bufio.NewReader(r)
+
+	var messages []*Message
+	for {
+		msg, err := decodeOne(br)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return messages, err
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+func decodeOne(br *bufio.Reader) (*Message, error) {
+	ind, err := readIndicatorSection(br)
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("grib2: section 0: %w", err)
+	}
+
+	body := make([]byte, ind.totalLength-16)
+	if _, err := io.ReadFull(br, body); err != nil {
+		return nil, fmt.Errorf("grib2: reading message body: %w", err)
+	}
+	buf := bytes.NewReader(body)
+
+	ids, err := readIdentificationSection(buf)
+	if err != nil {
+		return nil, fmt.Errorf("grib2: section 1: %w", err)
+	}
+
+	// Section 2 (Local Use) is optional and carries nothing this package
+	// reads; skip it when present.
+	var next [5]byte
+	if _, err := buf.ReadAt(next[:], buf.Size()-int64(buf.Len())); err == nil && next[4] == 2 {
+		if _, _, _, err := readSectionHeader(buf); err != nil {
+			return nil, fmt.Errorf("grib2: section 2: %w", err)
+		}
+	}
+
+	g, err := readGridDefinitionSection(buf)
+	if err != nil {
+		return nil, fmt.Errorf("grib2: section 3: %w", err)
+	}
+
+	pdt, err := readProductDefinitionSection(buf)
+	if err != nil {
+		return nil, fmt.Errorf("grib2: section 4: %w", err)
+	}
+
+	drt, err := readDataRepresentationSection(buf)
+	if err != nil {
+		return nil, fmt.Errorf("grib2: section 5: %w", err)
+	}
+
+	bitmap, err := readBitmapSection(buf, g.ni*g.nj)
+	if err != nil {
+		return nil, fmt.Errorf("grib2: section 6: %w", err)
+	}
+
+	values, err := readDataSection(buf, g.ni*g.nj, drt, bitmap)
+	if err != nil {
+		return nil, fmt.Errorf("grib2: section 7: %w", err)
+	}
+
+	if err := readEndSection(buf); err != nil {
+		return nil, fmt.Errorf("grib2: section 8: %w", err)
+	}
+
+	return &Message{
+		Discipline:    ind.discipline,
+		Category:      pdt.parameterCategory,
+		Number:        pdt.parameterNumber,
+		Level:         Level{Type: pdt.fixedSurfaceType1, Value: pdt.fixedSurfaceValue1},
+		ReferenceTime: ids.referenceTime,
+		ForecastTime:  pdt.forecastTime,
+		grid:          g,
+		values:        values,
+	}, nil
+}
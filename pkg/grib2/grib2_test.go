@@ -0,0 +1,61 @@
+package grib2
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ZygmuntJakub/gfs-stats-go/pkg/grib2/grib2test"
+)
+
+func TestDecode(t *testing.T) {
+	raw := grib2test.Build(grib2test.Message{
+		Year: 2024, Month: 6, Day: 1,
+		Ni: 2, Nj: 2,
+		La1: 50000000, Lo1: 200000000, // 50.0, 200.0 deg
+		La2: 49750000, Lo2: 200250000, // 49.75, 200.25 deg
+		Di: 250000, Dj: 250000, // 0.25 deg
+		Category: 0, Number: 0, // TMP
+		TimeRangeUnit: 1, ForecastValue: 6, // 6-hour forecast
+		SurfaceType: 103, SurfaceValue: 2, // 2 m above ground
+		Value: 301.5,
+	})
+
+	messages, err := Decode(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("got %d messages, want 1", len(messages))
+	}
+
+	msg := messages[0]
+
+	if got := msg.ParameterName(); got != "TMP" {
+		t.Errorf("ParameterName() = %q, want %q", got, "TMP")
+	}
+	if msg.Level.Type != 103 {
+		t.Errorf("Level.Type = %d, want 103 (above ground)", msg.Level.Type)
+	}
+	if msg.Level.Value != 2 {
+		t.Errorf("Level.Value = %v, want 2", msg.Level.Value)
+	}
+	if msg.ForecastTime != 6*time.Hour {
+		t.Errorf("ForecastTime = %v, want 6h", msg.ForecastTime)
+	}
+	wantRef := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	if !msg.ReferenceTime.Equal(wantRef) {
+		t.Errorf("ReferenceTime = %v, want %v", msg.ReferenceTime, wantRef)
+	}
+
+	if got := msg.ValueAt(50.0, 200.0); math.Abs(got-301.5) > 1e-3 {
+		t.Errorf("ValueAt(50.0, 200.0) = %v, want ~301.5", got)
+	}
+	if got := msg.ValueAt(49.75, 200.25); math.Abs(got-301.5) > 1e-3 {
+		t.Errorf("ValueAt(49.75, 200.25) = %v, want ~301.5", got)
+	}
+	if got := msg.ValueAt(0, 0); !math.IsNaN(got) {
+		t.Errorf("ValueAt(0, 0) = %v, want NaN (outside grid)", got)
+	}
+}
@@ -0,0 +1,116 @@
+// Package grib2test builds synthetic, spec-correct single-field GRIB2
+// messages for tests, so that pkg/grib2's own tests and its consumers'
+// tests exercise one shared, known-correct byte layout instead of each
+// maintaining their own copy that can silently drift from the spec (or
+// from each other) as section parsers change.
+package grib2test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// Message describes the single field a synthetic GRIB2 message should
+// carry. Every field is zero-valued by default, which is fine for tests
+// that don't care about it (e.g. ReferenceTime fields for a test that
+// only checks level matching).
+type Message struct {
+	Discipline uint8
+
+	// Section 1: Identification
+	Year                             int
+	Month, Day, Hour, Minute, Second int
+
+	// Section 3: Grid Definition, Template 3.0 (regular lat/lon grid).
+	// La1/Lo1/La2/Lo2/Di/Dj are in millionths of a degree, matching the
+	// raw on-the-wire encoding.
+	Ni, Nj                     uint32
+	La1, Lo1, La2, Lo2, Di, Dj uint32
+
+	// Section 4: Product Definition, Template 4.0
+	Category, Number uint8
+	TimeRangeUnit    uint8 // 0=minute, 1=hour, 2=day
+	ForecastValue    uint32
+	SurfaceType      uint8
+	SurfaceValue     uint32
+
+	// Section 5/7: Data Representation Template 5.0 (simple packing) and
+	// Data, as a constant field (every grid point equals Value).
+	Value float32
+}
+
+// Build assembles m into a complete, decodable GRIB2 message: Sections
+// 0, 1, 3, 4, 5, 6 (no bitmap) and 7 (a constant field, so Section 5's
+// bitsPerValue is 0 and no packed data is needed), followed by the "7777"
+// end marker.
+func Build(m Message) []byte {
+	section := func(number uint8, body []byte) []byte {
+		buf := make([]byte, 5+len(body))
+		binary.BigEndian.PutUint32(buf[0:4], uint32(5+len(body)))
+		buf[4] = number
+		copy(buf[5:], body)
+		return buf
+	}
+
+	body1 := make([]byte, 16)
+	binary.BigEndian.PutUint16(body1[7:9], uint16(m.Year))
+	body1[9] = byte(m.Month)
+	body1[10] = byte(m.Day)
+	body1[11] = byte(m.Hour)
+	body1[12] = byte(m.Minute)
+	body1[13] = byte(m.Second)
+	sec1 := section(1, body1)
+
+	body3 := make([]byte, 9+58) // template number (2 bytes) left 0: Template 3.0
+	tmpl3 := body3[9:]
+	binary.BigEndian.PutUint32(tmpl3[16:20], m.Ni)
+	binary.BigEndian.PutUint32(tmpl3[20:24], m.Nj)
+	binary.BigEndian.PutUint32(tmpl3[32:36], m.La1)
+	binary.BigEndian.PutUint32(tmpl3[36:40], m.Lo1)
+	binary.BigEndian.PutUint32(tmpl3[41:45], m.La2)
+	binary.BigEndian.PutUint32(tmpl3[45:49], m.Lo2)
+	binary.BigEndian.PutUint32(tmpl3[49:53], m.Di)
+	binary.BigEndian.PutUint32(tmpl3[53:57], m.Dj)
+	sec3 := section(3, body3)
+
+	body4 := make([]byte, 4+25) // template number (2 bytes) left 0: PDT 4.0
+	tmpl4 := body4[4:]
+	tmpl4[0] = m.Category
+	tmpl4[1] = m.Number
+	tmpl4[8] = m.TimeRangeUnit
+	binary.BigEndian.PutUint32(tmpl4[9:13], m.ForecastValue)
+	tmpl4[13] = m.SurfaceType
+	binary.BigEndian.PutUint32(tmpl4[15:19], m.SurfaceValue)
+	sec4 := section(4, body4)
+
+	body5 := make([]byte, 6+10) // template number (2 bytes) left 0: DRT 5.0
+	tmpl5 := body5[6:]
+	binary.BigEndian.PutUint32(tmpl5[0:4], math.Float32bits(m.Value))
+	tmpl5[8] = 0 // bits per value: constant field
+	sec5 := section(5, body5)
+
+	sec6 := section(6, []byte{255}) // no bitmap
+	sec7 := section(7, nil)         // constant field: no packed data needed
+
+	var body bytes.Buffer
+	body.Write(sec1)
+	body.Write(sec3)
+	body.Write(sec4)
+	body.Write(sec5)
+	body.Write(sec6)
+	body.Write(sec7)
+	body.WriteString("7777")
+
+	var full bytes.Buffer
+	full.WriteString("GRIB")
+	full.Write([]byte{0, 0}) // reserved
+	full.WriteByte(m.Discipline)
+	full.WriteByte(2) // edition
+	var lenBuf [8]byte
+	binary.BigEndian.PutUint64(lenBuf[:], uint64(16+body.Len()))
+	full.Write(lenBuf[:])
+	full.Write(body.Bytes())
+
+	return full.Bytes()
+}
@@ -0,0 +1,59 @@
+package grib2
+
+import "math"
+
+// interpolate performs bilinear interpolation of values (row-major, ni
+// columns by nj rows, first row at la1) at the given coordinate. It
+// returns NaN if the coordinate is outside the grid.
+func (g grid) interpolate(values []float64, lat, lon float64) float64 {
+	lon = normalizeLon(lon, g.lo1)
+
+	// GFS 0p25 grids scan west-to-east, north-to-south: row 0 is la1 (the
+	// northernmost latitude), so latitude decreases as the row index grows.
+	x := (lon - g.lo1) / g.di
+	y := (g.la1 - lat) / g.dj
+
+	if x < 0 || y < 0 || x > float64(g.ni-1) || y > float64(g.nj-1) {
+		return math.NaN()
+	}
+
+	x0 := int(math.Floor(x))
+	y0 := int(math.Floor(y))
+	x1 := minInt(x0+1, g.ni-1)
+	y1 := minInt(y0+1, g.nj-1)
+
+	fx := x - float64(x0)
+	fy := y - float64(y0)
+
+	v00 := g.at(values, x0, y0)
+	v10 := g.at(values, x1, y0)
+	v01 := g.at(values, x0, y1)
+	v11 := g.at(values, x1, y1)
+
+	top := v00*(1-fx) + v10*fx
+	bottom := v01*(1-fx) + v11*fx
+	return top*(1-fy) + bottom*fy
+}
+
+func (g grid) at(values []float64, x, y int) float64 {
+	return values[y*g.ni+x]
+}
+
+func normalizeLon(lon, gridOrigin float64) float64 {
+	// Grids are published on a 0-360 longitude axis; accept callers that
+	// pass -180..180 and translate into the grid's convention.
+	for lon < gridOrigin {
+		lon += 360
+	}
+	for lon >= gridOrigin+360 {
+		lon -= 360
+	}
+	return lon
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,31 @@
+package grib2
+
+import "fmt"
+
+// parameterKey identifies a GRIB2 parameter by discipline, category and
+// number, the triple Product Definition Template 4.0 encodes per message.
+type parameterKey struct {
+	discipline uint8
+	category   uint8
+	number     uint8
+}
+
+// knownParameters maps the subset of WMO parameter codes GFS 0p25 output
+// actually uses into the short names wgrib2 prints for them.
+var knownParameters = map[parameterKey]string{
+	{0, 0, 0}:  "TMP",   // Meteorological / Temperature / Temperature
+	{0, 2, 2}:  "UGRD",  // Meteorological / Momentum / U-component of wind
+	{0, 2, 3}:  "VGRD",  // Meteorological / Momentum / V-component of wind
+	{0, 2, 22}: "GUST",  // Meteorological / Momentum / Wind speed (gust)
+	{0, 6, 1}:  "TCDC",  // Meteorological / Cloud / Total cloud cover
+	{0, 1, 7}:  "PRATE", // Meteorological / Moisture / Precipitation rate
+}
+
+// parameterName returns the wgrib2-style short name for a parameter, or a
+// "<discipline>.<category>.<number>" placeholder if it isn't in the table.
+func parameterName(discipline, category, number uint8) string {
+	if name, ok := knownParameters[parameterKey{discipline, category, number}]; ok {
+		return name
+	}
+	return fmt.Sprintf("%d.%d.%d", discipline, category, number)
+}
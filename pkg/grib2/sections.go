@@ -0,0 +1,309 @@
+package grib2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+type indicatorSection struct {
+	discipline  uint8
+	edition     uint8
+	totalLength uint64
+}
+
+func readIndicatorSection(r io.Reader) (indicatorSection, error) {
+	var head [16]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return indicatorSection{}, io.EOF
+		}
+		return indicatorSection{}, err
+	}
+
+	if string(head[0:4]) != "GRIB" {
+		return indicatorSection{}, fmt.Errorf("missing GRIB magic, got %q", head[0:4])
+	}
+
+	ind := indicatorSection{
+		discipline:  head[6],
+		edition:     head[7],
+		totalLength: binary.BigEndian.Uint64(head[8:16]),
+	}
+
+	if ind.edition != 2 {
+		return ind, fmt.Errorf("unsupported GRIB edition %d", ind.edition)
+	}
+
+	return ind, nil
+}
+
+type identificationSection struct {
+	referenceTime time.Time
+}
+
+func readIdentificationSection(r *bytes.Reader) (identificationSection, error) {
+	length, number, body, err := readSectionHeader(r)
+	if err != nil {
+		return identificationSection{}, err
+	}
+	if number != 1 {
+		return identificationSection{}, fmt.Errorf("expected section 1, got %d", number)
+	}
+	_ = length
+
+	year := int(binary.BigEndian.Uint16(body[7:9]))
+	month := int(body[9])
+	day := int(body[10])
+	hour := int(body[11])
+	minute := int(body[12])
+	second := int(body[13])
+
+	return identificationSection{
+		referenceTime: time.Date(year, time.Month(month), day, hour, minute, second, 0, time.UTC),
+	}, nil
+}
+
+type grid struct {
+	ni, nj   int
+	la1, lo1 float64 // degrees
+	la2, lo2 float64
+	di, dj   float64 // degrees, always positive; scan direction applied in interpolate
+}
+
+func readGridDefinitionSection(r *bytes.Reader) (grid, error) {
+	_, number, body, err := readSectionHeader(r)
+	if err != nil {
+		return grid{}, err
+	}
+	if number != 3 {
+		return grid{}, fmt.Errorf("expected section 3, got %d", number)
+	}
+
+	templateNumber := binary.BigEndian.Uint16(body[7:9])
+	if templateNumber != 0 {
+		return grid{}, fmt.Errorf("unsupported grid definition template %d", templateNumber)
+	}
+
+	// Template 3.0 (regular lat/lon grid): 16 bytes of earth-shape fields
+	// precede Ni, and an 8-byte basic-angle/subdivisions block sits between
+	// Nj and La1.
+	tmpl := body[9:]
+	micro := 1e-6
+
+	g := grid{
+		ni:  int(binary.BigEndian.Uint32(tmpl[16:20])),
+		nj:  int(binary.BigEndian.Uint32(tmpl[20:24])),
+		la1: int32signed(binary.BigEndian.Uint32(tmpl[32:36])) * micro,
+		lo1: int32signed(binary.BigEndian.Uint32(tmpl[36:40])) * micro,
+		la2: int32signed(binary.BigEndian.Uint32(tmpl[41:45])) * micro,
+		lo2: int32signed(binary.BigEndian.Uint32(tmpl[45:49])) * micro,
+		di:  float64(binary.BigEndian.Uint32(tmpl[49:53])) * micro,
+		dj:  float64(binary.BigEndian.Uint32(tmpl[53:57])) * micro,
+	}
+
+	return g, nil
+}
+
+type productDefinitionSection struct {
+	parameterCategory  uint8
+	parameterNumber    uint8
+	forecastTime       time.Duration
+	fixedSurfaceType1  uint8
+	fixedSurfaceValue1 float64
+}
+
+func readProductDefinitionSection(r *bytes.Reader) (productDefinitionSection, error) {
+	_, number, body, err := readSectionHeader(r)
+	if err != nil {
+		return productDefinitionSection{}, err
+	}
+	if number != 4 {
+		return productDefinitionSection{}, fmt.Errorf("expected section 4, got %d", number)
+	}
+
+	templateNumber := binary.BigEndian.Uint16(body[2:4])
+	if templateNumber != 0 {
+		return productDefinitionSection{}, fmt.Errorf("unsupported product definition template %d", templateNumber)
+	}
+
+	tmpl := body[4:]
+	timeRangeUnit := tmpl[8]
+	forecastValue := binary.BigEndian.Uint32(tmpl[9:13])
+
+	var unit time.Duration
+	switch timeRangeUnit {
+	case 0: // minute
+		unit = time.Minute
+	case 1: // hour
+		unit = time.Hour
+	case 2: // day
+		unit = 24 * time.Hour
+	default:
+		unit = time.Hour
+	}
+
+	scale := tmpl[14]
+	value := binary.BigEndian.Uint32(tmpl[15:19])
+
+	return productDefinitionSection{
+		parameterCategory:  tmpl[0],
+		parameterNumber:    tmpl[1],
+		forecastTime:       time.Duration(forecastValue) * unit,
+		fixedSurfaceType1:  tmpl[13],
+		fixedSurfaceValue1: scaledValue(scale, value),
+	}, nil
+}
+
+type dataRepresentationSection struct {
+	templateNumber     uint16
+	referenceValue     float64
+	binaryScaleFactor  int16
+	decimalScaleFactor int16
+	bitsPerValue       uint8
+}
+
+func readDataRepresentationSection(r *bytes.Reader) (dataRepresentationSection, error) {
+	_, number, body, err := readSectionHeader(r)
+	if err != nil {
+		return dataRepresentationSection{}, err
+	}
+	if number != 5 {
+		return dataRepresentationSection{}, fmt.Errorf("expected section 5, got %d", number)
+	}
+
+	templateNumber := binary.BigEndian.Uint16(body[4:6])
+	tmpl := body[6:]
+
+	return dataRepresentationSection{
+		templateNumber:     templateNumber,
+		referenceValue:     float64(math.Float32frombits(binary.BigEndian.Uint32(tmpl[0:4]))),
+		binaryScaleFactor:  int16(binary.BigEndian.Uint16(tmpl[4:6])),
+		decimalScaleFactor: int16(binary.BigEndian.Uint16(tmpl[6:8])),
+		bitsPerValue:       tmpl[8],
+	}, nil
+}
+
+// readBitmapSection returns a mask of length n where true means "present".
+// A nil mask means no bitmap was defined, i.e. every point is present.
+func readBitmapSection(r *bytes.Reader, n int) ([]bool, error) {
+	_, number, body, err := readSectionHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if number != 6 {
+		return nil, fmt.Errorf("expected section 6, got %d", number)
+	}
+
+	indicator := body[0]
+	if indicator == 255 {
+		return nil, nil // no bitmap, nothing missing
+	}
+	if indicator != 0 {
+		return nil, fmt.Errorf("unsupported predefined bitmap %d", indicator)
+	}
+
+	bits := body[1:]
+	mask := make([]bool, n)
+	for i := 0; i < n; i++ {
+		mask[i] = bits[i/8]&(1<<uint(7-i%8)) != 0
+	}
+	return mask, nil
+}
+
+func readDataSection(r *bytes.Reader, n int, drt dataRepresentationSection, bitmap []bool) ([]float64, error) {
+	_, number, body, err := readSectionHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if number != 7 {
+		return nil, fmt.Errorf("expected section 7, got %d", number)
+	}
+
+	switch drt.templateNumber {
+	case 0:
+		return unpackSimple(body, n, drt, bitmap)
+	case 40:
+		return nil, ErrUnsupportedPacking
+	default:
+		return nil, fmt.Errorf("%w: template 5.%d", ErrUnsupportedPacking, drt.templateNumber)
+	}
+}
+
+// unpackSimple decodes GRIB2 Data Representation Template 5.0 (simple
+// packing): each grid point is a fixed-width unsigned integer X, and the
+// physical value is (R + X*2^E) / 10^D.
+func unpackSimple(data []byte, n int, drt dataRepresentationSection, bitmap []bool) ([]float64, error) {
+	values := make([]float64, n)
+	if drt.bitsPerValue == 0 {
+		// Constant field: every present point equals the reference value.
+		for i := range values {
+			if bitmap != nil && !bitmap[i] {
+				values[i] = math.NaN()
+			} else {
+				values[i] = drt.referenceValue
+			}
+		}
+		return values, nil
+	}
+
+	br := newBitReader(data)
+	binScale := math.Pow(2, float64(drt.binaryScaleFactor))
+	decScale := math.Pow(10, float64(drt.decimalScaleFactor))
+
+	for i := 0; i < n; i++ {
+		if bitmap != nil && !bitmap[i] {
+			values[i] = math.NaN()
+			continue
+		}
+		raw, err := br.read(int(drt.bitsPerValue))
+		if err != nil {
+			return nil, fmt.Errorf("unpacking value %d: %w", i, err)
+		}
+		values[i] = (drt.referenceValue + float64(raw)*binScale) / decScale
+	}
+	return values, nil
+}
+
+func readEndSection(r *bytes.Reader) error {
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return err
+	}
+	if string(magic) != "7777" {
+		return fmt.Errorf("missing 7777 end marker, got %q", magic)
+	}
+	return nil
+}
+
+func readSectionHeader(r *bytes.Reader) (length uint32, number uint8, body []byte, err error) {
+	var head [5]byte
+	if _, err = io.ReadFull(r, head[:]); err != nil {
+		return 0, 0, nil, err
+	}
+	length = binary.BigEndian.Uint32(head[0:4])
+	number = head[4]
+
+	body = make([]byte, length-5)
+	if _, err = io.ReadFull(r, body); err != nil {
+		return 0, 0, nil, err
+	}
+	return length, number, body, nil
+}
+
+func int32signed(v uint32) float64 {
+	if v&0x80000000 != 0 {
+		return -float64(v &^ 0x80000000)
+	}
+	return float64(v)
+}
+
+func scaledValue(scale uint8, value uint32) float64 {
+	if scale == 0 {
+		return float64(value)
+	}
+	return float64(value) / math.Pow(10, float64(scale))
+}
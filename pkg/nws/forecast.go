@@ -0,0 +1,200 @@
+// Package nws shapes GFS-derived forecast data into the same JSON
+// structure the National Weather Service API returns from its
+// /points/{lat},{lon}/forecast and /forecast/hourly endpoints, so
+// existing NWS API clients can point at this service with no changes.
+package nws
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ZygmuntJakub/gfs-stats-go/pkg/solar"
+)
+
+const knotsToMph = 1.15078
+
+// Forecast mirrors the top-level shape of an NWS gridpoint forecast
+// response.
+type Forecast struct {
+	Properties Properties `json:"properties"`
+}
+
+// Properties holds the ordered list of forecast periods.
+type Properties struct {
+	Periods []Period `json:"periods"`
+}
+
+// Period is one NWS-style forecast period: an hour for the hourly
+// endpoint, or a 12-hour day/night block for the multi-period endpoint.
+type Period struct {
+	Number           int       `json:"number"`
+	Name             string    `json:"name"`
+	StartTime        time.Time `json:"startTime"`
+	EndTime          time.Time `json:"endTime"`
+	IsDayTime        bool      `json:"isDaytime"`
+	Temperature      int       `json:"temperature"`
+	TemperatureUnit  string    `json:"temperatureUnit"`
+	WindSpeed        string    `json:"windSpeed"`
+	WindDirection    string    `json:"windDirection"`
+	ShortForecast    string    `json:"shortForecast"`
+	DetailedForecast string    `json:"detailedForecast"`
+}
+
+// HourlyPoint is one hour of decoded GFS output at a single coordinate,
+// the input BuildForecast aggregates into periods.
+type HourlyPoint struct {
+	Time            time.Time
+	Lat, Lon        float64
+	TempC           float64
+	WindKt          float64
+	GustKt          float64
+	Direction       string
+	CloudCoverPct   float64
+	PrecipRateKgM2S float64
+}
+
+// BuildForecast groups points into consecutive buckets of periodHours
+// hours (1 for /forecast/hourly, 12 for /forecast/periods) and summarizes
+// each bucket into a Period. points must be sorted by Time ascending.
+func BuildForecast(points []HourlyPoint, periodHours int) Forecast {
+	if periodHours <= 0 {
+		periodHours = 1
+	}
+
+	var periods []Period
+	for i := 0; i < len(points); i += periodHours {
+		end := i + periodHours
+		if end > len(points) {
+			end = len(points)
+		}
+		periods = append(periods, buildPeriod(points[i:end], len(periods)))
+	}
+
+	return Forecast{Properties: Properties{Periods: periods}}
+}
+
+func buildPeriod(bucket []HourlyPoint, index int) Period {
+	start := bucket[0].Time
+	end := bucket[len(bucket)-1].Time.Add(time.Hour)
+	mid := start.Add(end.Sub(start) / 2)
+	isDay := solar.IsDaytime(mid, bucket[0].Lat, bucket[0].Lon)
+
+	var sumTempC, sumWindKt, sumCloudPct, sumPrecip float64
+	for _, p := range bucket {
+		sumTempC += p.TempC
+		sumWindKt += p.WindKt
+		sumCloudPct += p.CloudCoverPct
+		sumPrecip += p.PrecipRateKgM2S
+	}
+	n := float64(len(bucket))
+	avgTempF := celsiusToFahrenheit(sumTempC / n)
+	avgWindMph := (sumWindKt / n) * knotsToMph
+	avgCloudPct := sumCloudPct / n
+	avgPrecip := sumPrecip / n
+	dir := modeDirection(bucket)
+
+	short := shortForecast(avgCloudPct, avgPrecip, isDay)
+
+	return Period{
+		Number:           index + 1,
+		Name:             periodName(start, isDay, index),
+		StartTime:        start,
+		EndTime:          end,
+		IsDayTime:        isDay,
+		Temperature:      int(math.Round(avgTempF)),
+		TemperatureUnit:  "F",
+		WindSpeed:        fmt.Sprintf("%.0f mph", avgWindMph),
+		WindDirection:    dir,
+		ShortForecast:    short,
+		DetailedForecast: detailedForecast(short, avgTempF, avgWindMph, dir),
+	}
+}
+
+func periodName(start time.Time, isDay bool, index int) string {
+	if index == 0 {
+		if isDay {
+			return "Today"
+		}
+		return "Tonight"
+	}
+
+	name := start.Weekday().String()
+	if !isDay {
+		name += " Night"
+	}
+	return name
+}
+
+// modeDirection returns the most frequent cardinal direction in bucket,
+// breaking ties in favor of whichever direction appeared first.
+func modeDirection(bucket []HourlyPoint) string {
+	counts := make(map[string]int, len(bucket))
+	var order []string
+	for _, p := range bucket {
+		if _, ok := counts[p.Direction]; !ok {
+			order = append(order, p.Direction)
+		}
+		counts[p.Direction]++
+	}
+
+	best := order[0]
+	for _, d := range order[1:] {
+		if counts[d] > counts[best] {
+			best = d
+		}
+	}
+	return best
+}
+
+// shortForecast synthesizes an NWS-style short forecast string from
+// average cloud cover and precipitation rate, the two fields GFS can give
+// us without a full weather-type product (GFS 0p25 has no "present
+// weather" parameter).
+func shortForecast(cloudCoverPct, precipRateKgM2S float64, isDay bool) string {
+	precipMMHr := precipRateKgM2S * 3600 // kg/m^2/s of liquid water == mm/s
+
+	switch {
+	case precipMMHr >= 2.5:
+		return "Rain"
+	case precipMMHr >= 0.1:
+		return "Chance Rain"
+	default:
+		return skyCoverText(cloudCoverPct, isDay)
+	}
+}
+
+// skyCoverText buckets total cloud cover using the same breakpoints NWS
+// uses to pick between "Sunny"/"Mostly Sunny"/"Partly Sunny"/"Mostly
+// Cloudy"/"Cloudy" (and their night equivalents).
+func skyCoverText(cloudCoverPct float64, isDay bool) string {
+	switch {
+	case cloudCoverPct < 6:
+		if isDay {
+			return "Sunny"
+		}
+		return "Clear"
+	case cloudCoverPct < 31:
+		if isDay {
+			return "Mostly Sunny"
+		}
+		return "Mostly Clear"
+	case cloudCoverPct < 69:
+		if isDay {
+			return "Partly Sunny"
+		}
+		return "Partly Cloudy"
+	case cloudCoverPct < 94:
+		return "Mostly Cloudy"
+	default:
+		return "Cloudy"
+	}
+}
+
+func detailedForecast(short string, tempF, windMph float64, dir string) string {
+	return fmt.Sprintf("%s, with a temperature around %.0f°F. Wind %s around %.0f mph.", short, tempF, dir, windMph)
+}
+
+func celsiusToFahrenheit(c float64) float64 {
+	return c*9/5 + 32
+}
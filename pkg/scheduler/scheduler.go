@@ -0,0 +1,121 @@
+// Package scheduler runs a gfs.Downloader automatically a few minutes
+// after each new GFS cycle is published, so a long-running server always
+// has a fresh forecast on disk without an operator re-triggering the CLI.
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ZygmuntJakub/gfs-stats-go/pkg/gfs"
+)
+
+// publishHoursUTC are the times of day NOMADS typically has finished
+// publishing the 00/06/12/18Z cycles, the same hours at which
+// gfs.CurrentCycle starts reporting each one as current.
+var publishHoursUTC = []int{5, 11, 17, 23}
+
+// Scheduler periodically runs a Downloader and remembers the last cycle
+// it successfully fetched, so a restart doesn't re-download data that's
+// already on disk.
+type Scheduler struct {
+	Downloader *gfs.Downloader
+	StateFile  string
+
+	mu sync.Mutex
+}
+
+// NewScheduler returns a Scheduler that drives downloader and records its
+// progress in stateFile.
+func NewScheduler(downloader *gfs.Downloader, stateFile string) *Scheduler {
+	return &Scheduler{Downloader: downloader, StateFile: stateFile}
+}
+
+type state struct {
+	Date  string `json:"date"`
+	Cycle string `json:"cycle"`
+}
+
+// Start blocks, running an immediate catch-up fetch and then firing again
+// at each of publishHoursUTC, until ctx is canceled.
+func (s *Scheduler) Start(ctx context.Context) {
+	if err := s.RunOnce(ctx); err != nil {
+		log.Printf("scheduler: initial fetch failed: %v", err)
+	}
+
+	for {
+		next := nextFireTime(time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(next)):
+			if err := s.RunOnce(ctx); err != nil {
+				log.Printf("scheduler: fetch failed: %v", err)
+			}
+		}
+	}
+}
+
+// RunOnce downloads the current GFS cycle if it isn't already the last
+// one recorded in StateFile, then updates StateFile on success. The
+// Downloader's own atomic rename means in-flight /forecast requests keep
+// reading the previous cycle's directory until the new one is fully in
+// place.
+func (s *Scheduler) RunOnce(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	date, cycle := gfs.CurrentCycle(time.Now())
+
+	if last, ok := s.readState(); ok && last.Date == date && last.Cycle == cycle {
+		return nil
+	}
+
+	if err := s.Downloader.Run(ctx, date, cycle); err != nil {
+		return fmt.Errorf("downloading cycle %s %sZ: %w", date, cycle, err)
+	}
+
+	return s.writeState(state{Date: date, Cycle: cycle})
+}
+
+func (s *Scheduler) readState() (state, bool) {
+	data, err := os.ReadFile(s.StateFile)
+	if err != nil {
+		return state{}, false
+	}
+
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return state{}, false
+	}
+	return st, true
+}
+
+func (s *Scheduler) writeState(st state) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.StateFile, data, 0644)
+}
+
+// nextFireTime returns the next publishHoursUTC instant strictly after now.
+func nextFireTime(now time.Time) time.Time {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	for _, h := range publishHoursUTC {
+		candidate := today.Add(time.Duration(h) * time.Hour)
+		if candidate.After(now) {
+			return candidate
+		}
+	}
+
+	// Every publish hour today has passed; the next one is tomorrow's first.
+	return today.Add(24 * time.Hour).Add(time.Duration(publishHoursUTC[0]) * time.Hour)
+}
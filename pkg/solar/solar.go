@@ -0,0 +1,65 @@
+// Package solar computes low-precision solar position, good enough to
+// tell day from night for a given time and coordinate. It implements the
+// NOAA General Solar Position Calculations, which trade a fraction of a
+// degree of accuracy for a closed-form expression with no ephemeris data.
+package solar
+
+import (
+	"math"
+	"time"
+)
+
+// refractionCorrectionDeg is the standard atmospheric refraction
+// adjustment applied at the horizon: the sun is still visible for a few
+// minutes after its geometric center crosses 0° elevation.
+const refractionCorrectionDeg = -0.833
+
+// Position returns the sun's elevation and azimuth, in degrees, at time t
+// (evaluated in UTC) for the given coordinate.
+func Position(t time.Time, lat, lon float64) (elevationDeg, azimuthDeg float64) {
+	t = t.UTC()
+
+	latRad := lat * math.Pi / 180
+
+	dayFraction := (float64(t.Hour()*3600+t.Minute()*60+t.Second()) / 86400)
+	gamma := 2 * math.Pi / 365 * (float64(t.YearDay()-1) + dayFraction)
+
+	eqTime := 229.18 * (0.000075 +
+		0.001868*math.Cos(gamma) -
+		0.032077*math.Sin(gamma) -
+		0.014615*math.Cos(2*gamma) -
+		0.040849*math.Sin(2*gamma))
+
+	decl := 0.006918 -
+		0.399912*math.Cos(gamma) + 0.070257*math.Sin(gamma) -
+		0.006758*math.Cos(2*gamma) + 0.000907*math.Sin(2*gamma) -
+		0.002697*math.Cos(3*gamma) + 0.00148*math.Sin(3*gamma)
+
+	timeOffset := eqTime + 4*lon // minutes; longitude maps UTC clock time to local solar time
+	trueSolarTime := float64(t.Hour()*60+t.Minute()) + float64(t.Second())/60 + timeOffset
+
+	hourAngleDeg := trueSolarTime/4 - 180
+	hourAngle := hourAngleDeg * math.Pi / 180
+
+	cosZenith := math.Sin(latRad)*math.Sin(decl) + math.Cos(latRad)*math.Cos(decl)*math.Cos(hourAngle)
+	cosZenith = math.Max(-1, math.Min(1, cosZenith))
+	zenith := math.Acos(cosZenith)
+
+	elevationDeg = 90 - zenith*180/math.Pi
+
+	cosAzimuth := (math.Sin(decl) - math.Sin(latRad)*math.Cos(zenith)) / (math.Cos(latRad) * math.Sin(zenith))
+	cosAzimuth = math.Max(-1, math.Min(1, cosAzimuth))
+	azimuthDeg = math.Acos(cosAzimuth) * 180 / math.Pi
+	if hourAngleDeg > 0 {
+		azimuthDeg = 360 - azimuthDeg
+	}
+
+	return elevationDeg, azimuthDeg
+}
+
+// IsDaytime reports whether the sun is above the horizon, accounting for
+// atmospheric refraction, at time t and the given coordinate.
+func IsDaytime(t time.Time, lat, lon float64) bool {
+	elevation, _ := Position(t, lat, lon)
+	return elevation > refractionCorrectionDeg
+}